@@ -0,0 +1,6 @@
+package model
+
+// MssqlDBType is the fourth supported Config.DBType / sqlstore.Params.DBType
+// value, alongside the pre-existing MysqlDBType, PostgresDBType and
+// SqliteDBType declared elsewhere in this package.
+const MssqlDBType = "mssql"
@@ -0,0 +1,84 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sync"
+
+	"github.com/mattermost/focalboard/server/model"
+)
+
+// Backend is the minimal surface a storage engine has to provide in order
+// to back a Store. It deliberately knows nothing about squirrel or SQL —
+// sqlstore satisfies it by wrapping its existing *sql.DB-based code, and
+// non-SQL engines (boltstore) satisfy it directly, so both can sit behind
+// the same registry.
+type Backend interface {
+	// Session returns a handle scoped to the lifetime of a single
+	// logical unit of work. For the SQL backend this is a thin wrapper
+	// around the shared *sql.DB; for an embedded KV backend it may be the
+	// database handle itself.
+	Session(ctx context.Context) Session
+
+	// RunMigrations brings the backend's schema up to date. It must only
+	// ever touch the primary/writable copy of the data.
+	RunMigrations() error
+
+	// Tx runs fn inside a backend-native transaction, committing on a nil
+	// return and rolling back otherwise.
+	Tx(ctx context.Context, fn func(Session) error) error
+
+	// Shutdown releases any resources (connections, file handles) held by
+	// the backend.
+	Shutdown() error
+}
+
+// Session is a backend handle bound to the current unit of work (either the
+// backend's ambient connection, or one pinned to an in-flight Tx). Its
+// method set is the block surface every Backend must expose through
+// Session regardless of whether it's SQL-based or not, so a caller holding
+// a Session can work against it without type-asserting back to the
+// concrete backend. Board/Session/User coverage is follow-up work.
+type Session interface {
+	GetBlock(id string) (*model.Block, error)
+	InsertBlock(block *model.Block) error
+	DeleteBlock(id string) error
+}
+
+// BackendFactory constructs a Backend from a parsed connection URL. dsn is
+// the full, unparsed connection string, passed through as-is since SQL
+// drivers want the raw string rather than a re-assembled URL.
+type BackendFactory func(dsn string, u *url.URL) (Backend, error)
+
+var (
+	registryMu sync.Mutex
+	registry   = map[string]BackendFactory{}
+)
+
+// RegisterBackend makes a backend available under the given URL scheme,
+// e.g. "postgres", "mysql", "sqlite", "bolt". Backend packages call this
+// from an init() so selecting one is just a matter of importing it.
+func RegisterBackend(scheme string, factory BackendFactory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[scheme] = factory
+}
+
+// OpenBackend parses dsn's scheme and dispatches to whichever backend
+// registered for it, e.g. "postgres://..." or "bolt:///var/lib/focalboard.db".
+func OpenBackend(dsn string) (Backend, error) {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("invalid connection string: %w", err)
+	}
+
+	registryMu.Lock()
+	factory, ok := registry[u.Scheme]
+	registryMu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("no backend registered for scheme %q", u.Scheme)
+	}
+
+	return factory(dsn, u)
+}
@@ -0,0 +1,32 @@
+package store
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterAndOpenBackend(t *testing.T) {
+	registryMu.Lock()
+	delete(registry, "memtest")
+	registryMu.Unlock()
+
+	var gotDSN string
+	var gotScheme string
+	RegisterBackend("memtest", func(dsn string, u *url.URL) (Backend, error) {
+		gotDSN = dsn
+		gotScheme = u.Scheme
+		return nil, nil
+	})
+
+	_, err := OpenBackend("memtest://some/path")
+	require.NoError(t, err)
+	require.Equal(t, "memtest://some/path", gotDSN)
+	require.Equal(t, "memtest", gotScheme)
+}
+
+func TestOpenBackendUnknownScheme(t *testing.T) {
+	_, err := OpenBackend("notregistered://some/path")
+	require.Error(t, err)
+}
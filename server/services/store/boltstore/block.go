@@ -0,0 +1,48 @@
+package boltstore
+
+import (
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/mattermost/focalboard/server/model"
+)
+
+func getBlock(a accessor, id string) (*model.Block, error) {
+	var block model.Block
+	if err := a.view(func(tx *bolt.Tx) error {
+		return getJSON(tx, blocksBucket, id, &block)
+	}); err != nil {
+		return nil, err
+	}
+	return &block, nil
+}
+
+func insertBlock(a accessor, block *model.Block) error {
+	return a.update(func(tx *bolt.Tx) error {
+		return putJSON(tx, blocksBucket, block.ID, block)
+	})
+}
+
+func deleteBlock(a accessor, id string) error {
+	return a.update(func(tx *bolt.Tx) error {
+		return deleteKey(tx, blocksBucket, id)
+	})
+}
+
+// GetBlock returns the block with the given ID, or ErrNotFound if no such
+// block exists.
+func (s *Store) GetBlock(id string) (*model.Block, error) { return getBlock(s, id) }
+
+// InsertBlock creates or replaces the block keyed by block.ID.
+func (s *Store) InsertBlock(block *model.Block) error { return insertBlock(s, block) }
+
+// DeleteBlock removes the block with the given ID, if any.
+func (s *Store) DeleteBlock(id string) error { return deleteBlock(s, id) }
+
+// GetBlock is the Tx-scoped equivalent of (*Store).GetBlock.
+func (t *txSession) GetBlock(id string) (*model.Block, error) { return getBlock(t, id) }
+
+// InsertBlock is the Tx-scoped equivalent of (*Store).InsertBlock.
+func (t *txSession) InsertBlock(block *model.Block) error { return insertBlock(t, block) }
+
+// DeleteBlock is the Tx-scoped equivalent of (*Store).DeleteBlock.
+func (t *txSession) DeleteBlock(id string) error { return deleteBlock(t, id) }
@@ -0,0 +1,48 @@
+package boltstore
+
+import (
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/mattermost/focalboard/server/model"
+)
+
+func getBoard(a accessor, id string) (*model.Board, error) {
+	var board model.Board
+	if err := a.view(func(tx *bolt.Tx) error {
+		return getJSON(tx, boardsBucket, id, &board)
+	}); err != nil {
+		return nil, err
+	}
+	return &board, nil
+}
+
+func insertBoard(a accessor, board *model.Board) error {
+	return a.update(func(tx *bolt.Tx) error {
+		return putJSON(tx, boardsBucket, board.ID, board)
+	})
+}
+
+func deleteBoard(a accessor, id string) error {
+	return a.update(func(tx *bolt.Tx) error {
+		return deleteKey(tx, boardsBucket, id)
+	})
+}
+
+// GetBoard returns the board with the given ID, or ErrNotFound if no such
+// board exists.
+func (s *Store) GetBoard(id string) (*model.Board, error) { return getBoard(s, id) }
+
+// InsertBoard creates or replaces the board keyed by board.ID.
+func (s *Store) InsertBoard(board *model.Board) error { return insertBoard(s, board) }
+
+// DeleteBoard removes the board with the given ID, if any.
+func (s *Store) DeleteBoard(id string) error { return deleteBoard(s, id) }
+
+// GetBoard is the Tx-scoped equivalent of (*Store).GetBoard.
+func (t *txSession) GetBoard(id string) (*model.Board, error) { return getBoard(t, id) }
+
+// InsertBoard is the Tx-scoped equivalent of (*Store).InsertBoard.
+func (t *txSession) InsertBoard(board *model.Board) error { return insertBoard(t, board) }
+
+// DeleteBoard is the Tx-scoped equivalent of (*Store).DeleteBoard.
+func (t *txSession) DeleteBoard(id string) error { return deleteBoard(t, id) }
@@ -0,0 +1,58 @@
+package boltstore
+
+import (
+	bolt "go.etcd.io/bbolt"
+
+	mmModel "github.com/mattermost/mattermost-server/v6/model"
+)
+
+func getSession(a accessor, token string) (*mmModel.Session, error) {
+	var session mmModel.Session
+	if err := a.view(func(tx *bolt.Tx) error {
+		return getJSON(tx, sessionsBucket, token, &session)
+	}); err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func putSession(a accessor, session *mmModel.Session) error {
+	return a.update(func(tx *bolt.Tx) error {
+		return putJSON(tx, sessionsBucket, session.Token, session)
+	})
+}
+
+func deleteSession(a accessor, token string) error {
+	return a.update(func(tx *bolt.Tx) error {
+		return deleteKey(tx, sessionsBucket, token)
+	})
+}
+
+// GetSession returns the session with the given token, or ErrNotFound if
+// no such session exists.
+func (s *Store) GetSession(token string) (*mmModel.Session, error) { return getSession(s, token) }
+
+// CreateSession stores session, keyed by its token.
+func (s *Store) CreateSession(session *mmModel.Session) error { return putSession(s, session) }
+
+// RefreshSession updates the stored session in place, e.g. to bump its
+// expiry. It shares CreateSession's write path, since both simply
+// overwrite the record.
+func (s *Store) RefreshSession(session *mmModel.Session) error { return putSession(s, session) }
+
+// DeleteSession removes the session with the given token, if any.
+func (s *Store) DeleteSession(token string) error { return deleteSession(s, token) }
+
+// GetSession is the Tx-scoped equivalent of (*Store).GetSession.
+func (t *txSession) GetSession(token string) (*mmModel.Session, error) {
+	return getSession(t, token)
+}
+
+// CreateSession is the Tx-scoped equivalent of (*Store).CreateSession.
+func (t *txSession) CreateSession(session *mmModel.Session) error { return putSession(t, session) }
+
+// RefreshSession is the Tx-scoped equivalent of (*Store).RefreshSession.
+func (t *txSession) RefreshSession(session *mmModel.Session) error { return putSession(t, session) }
+
+// DeleteSession is the Tx-scoped equivalent of (*Store).DeleteSession.
+func (t *txSession) DeleteSession(token string) error { return deleteSession(t, token) }
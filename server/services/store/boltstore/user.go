@@ -0,0 +1,48 @@
+package boltstore
+
+import (
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/mattermost/focalboard/server/model"
+)
+
+func getUser(a accessor, id string) (*model.User, error) {
+	var user model.User
+	if err := a.view(func(tx *bolt.Tx) error {
+		return getJSON(tx, usersBucket, id, &user)
+	}); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func createUser(a accessor, user *model.User) error {
+	return a.update(func(tx *bolt.Tx) error {
+		return putJSON(tx, usersBucket, user.ID, user)
+	})
+}
+
+func deleteUser(a accessor, id string) error {
+	return a.update(func(tx *bolt.Tx) error {
+		return deleteKey(tx, usersBucket, id)
+	})
+}
+
+// GetUser returns the user with the given ID, or ErrNotFound if no such
+// user exists.
+func (s *Store) GetUser(id string) (*model.User, error) { return getUser(s, id) }
+
+// CreateUser creates or replaces the user keyed by user.ID.
+func (s *Store) CreateUser(user *model.User) error { return createUser(s, user) }
+
+// DeleteUser removes the user with the given ID, if any.
+func (s *Store) DeleteUser(id string) error { return deleteUser(s, id) }
+
+// GetUser is the Tx-scoped equivalent of (*Store).GetUser.
+func (t *txSession) GetUser(id string) (*model.User, error) { return getUser(t, id) }
+
+// CreateUser is the Tx-scoped equivalent of (*Store).CreateUser.
+func (t *txSession) CreateUser(user *model.User) error { return createUser(t, user) }
+
+// DeleteUser is the Tx-scoped equivalent of (*Store).DeleteUser.
+func (t *txSession) DeleteUser(id string) error { return deleteUser(t, id) }
@@ -0,0 +1,109 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/store"
+	"github.com/mattermost/mattermost-server/v6/shared/mlog"
+)
+
+func init() {
+	for _, scheme := range []string{"postgres", "mysql", "sqlite", "sqlite3", "mssql", "sqlserver"} {
+		store.RegisterBackend(scheme, openBackend)
+	}
+}
+
+// openBackend adapts the existing sqlstore package to the store.Backend
+// registry, so selecting a SQL backend is just a matter of its connection
+// string's scheme matching one of the ones registered above. It's the SQL
+// counterpart to boltstore.openBackend. Wiring focalboard-server's
+// config.json through to store.OpenBackend is follow-up work; nothing in
+// the server yet constructs a backend via the registry.
+func openBackend(dsn string, u *url.URL) (store.Backend, error) {
+	dbType, err := dbTypeForScheme(u.Scheme)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := sql.Open(dbType, driverDSN(dbType, dsn, u))
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := New(Params{
+		DBType:           dbType,
+		ConnectionString: dsn,
+		TablePrefix:      "focalboard_",
+		Logger:           mlog.CreateConsoleLogger(false, "info"),
+		DB:               db,
+	})
+	if err != nil {
+		// New failed (e.g. Migrate), so nothing owns db yet; close it
+		// ourselves rather than leaking the connection pool.
+		db.Close()
+		return nil, err
+	}
+	return &backendAdapter{store: s}, nil
+}
+
+func dbTypeForScheme(scheme string) (string, error) {
+	switch scheme {
+	case "postgres":
+		return model.PostgresDBType, nil
+	case "mysql":
+		return model.MysqlDBType, nil
+	case "sqlite", "sqlite3":
+		return model.SqliteDBType, nil
+	case "mssql", "sqlserver":
+		return model.MssqlDBType, nil
+	default:
+		return "", fmt.Errorf("unrecognized scheme %q for sqlstore backend", scheme)
+	}
+}
+
+// driverDSN converts the registry's scheme-qualified dsn into whatever
+// format each driver's sql.Open expects: lib/pq and the sqlite3 driver are
+// happy with the full URL, while go-sql-driver/mysql wants the
+// scheme stripped off.
+func driverDSN(dbType, dsn string, u *url.URL) string {
+	if dbType == model.MysqlDBType {
+		return strings.TrimPrefix(dsn, u.Scheme+"://")
+	}
+	return dsn
+}
+
+// backendAdapter satisfies store.Backend by delegating to the existing
+// *SQLStore, so New keeps its current signature and callers that construct
+// a SQLStore directly (rather than through the registry) are unaffected.
+type backendAdapter struct {
+	store *SQLStore
+}
+
+func (b *backendAdapter) Session(ctx context.Context) store.Session {
+	return &sqlSession{store: b.store, db: b.store.db}
+}
+
+func (b *backendAdapter) RunMigrations() error {
+	return b.store.Migrate()
+}
+
+func (b *backendAdapter) Tx(ctx context.Context, fn func(store.Session) error) error {
+	tx, err := b.store.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := fn(&sqlSession{store: b.store, db: tx}); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func (b *backendAdapter) Shutdown() error {
+	return b.store.Shutdown()
+}
@@ -0,0 +1,170 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/store"
+)
+
+// fakeBlockStore is the shared backing map for fakeBlockConn, keyed by
+// block ID, so every connection handed out by fakeBlockDriver sees the
+// same data.
+type fakeBlockStore struct {
+	mu   sync.Mutex
+	rows map[string][]driver.Value
+}
+
+type fakeBlockDriver struct{ store *fakeBlockStore }
+
+func (d fakeBlockDriver) Open(name string) (driver.Conn, error) {
+	return &fakeBlockConn{store: d.store}, nil
+}
+
+// fakeBlockConn is a minimal database/sql driver.Conn that serves the
+// blocks table InsertBlock/GetBlock/DeleteBlock generate queries against,
+// so sqlSession's block methods can be exercised without a real database.
+type fakeBlockConn struct{ store *fakeBlockStore }
+
+func (c *fakeBlockConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("fakeBlockConn: Prepare not implemented, use ExecerContext/QueryerContext")
+}
+func (c *fakeBlockConn) Close() error { return nil }
+func (c *fakeBlockConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("fakeBlockConn: Begin not implemented")
+}
+
+func (c *fakeBlockConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.store.mu.Lock()
+	defer c.store.mu.Unlock()
+
+	switch {
+	case strings.Contains(query, "DELETE"):
+		id, _ := args[0].Value.(string)
+		delete(c.store.rows, id)
+		return driver.RowsAffected(1), nil
+	case strings.Contains(query, "INSERT"):
+		row := make([]driver.Value, len(args))
+		for i, a := range args {
+			row[i] = a.Value
+		}
+		id, _ := row[0].(string)
+		c.store.rows[id] = row
+		return driver.RowsAffected(1), nil
+	case strings.Contains(query, "UPDATE"):
+		// Squirrel orders an UPDATE's bind args as SET values followed by
+		// WHERE values, so id (the only WHERE value upsertBlockCheckThenUpdate
+		// uses) is the last one.
+		id, _ := args[len(args)-1].Value.(string)
+		if _, ok := c.store.rows[id]; !ok {
+			return driver.RowsAffected(0), nil
+		}
+		row := make([]driver.Value, len(blockColumns))
+		row[0] = id
+		for i, a := range args[:len(args)-1] {
+			row[i+1] = a.Value
+		}
+		c.store.rows[id] = row
+		return driver.RowsAffected(1), nil
+	default:
+		return nil, fmt.Errorf("fakeBlockConn: unsupported exec query %q", query)
+	}
+}
+
+func (c *fakeBlockConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	c.store.mu.Lock()
+	defer c.store.mu.Unlock()
+
+	id, _ := args[0].Value.(string)
+	row, ok := c.store.rows[id]
+	return &fakeBlockRows{row: row, found: ok}, nil
+}
+
+type fakeBlockRows struct {
+	row   []driver.Value
+	found bool
+	done  bool
+}
+
+func (r *fakeBlockRows) Columns() []string { return blockColumns }
+func (r *fakeBlockRows) Close() error      { return nil }
+func (r *fakeBlockRows) Next(dest []driver.Value) error {
+	if !r.found || r.done {
+		return io.EOF
+	}
+	copy(dest, r.row)
+	r.done = true
+	return nil
+}
+
+// newTestSQLSession registers a fresh fakeBlockDriver under a unique name
+// and returns a sqlSession backed by it, so each test gets its own
+// isolated blocks table.
+func newTestSQLSession(t *testing.T) *sqlSession {
+	t.Helper()
+	return newTestSQLSessionWithDialect(t, sqliteDialect{})
+}
+
+func newTestSQLSessionWithDialect(t *testing.T, dialect Dialect) *sqlSession {
+	t.Helper()
+
+	driverName := "fakeBlockDriver-" + t.Name()
+	sql.Register(driverName, fakeBlockDriver{store: &fakeBlockStore{rows: map[string][]driver.Value{}}})
+
+	db, err := sql.Open(driverName, "fake")
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, db.Close()) })
+
+	s := &SQLStore{db: db, dialect: dialect}
+	return &sqlSession{store: s, db: db}
+}
+
+func TestSQLSessionSatisfiesStoreSession(t *testing.T) {
+	var _ store.Session = (*sqlSession)(nil)
+}
+
+func TestSQLSessionBlockRoundTrip(t *testing.T) {
+	s := newTestSQLSession(t)
+
+	_, err := s.GetBlock("missing")
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	block := &model.Block{ID: "block-1", Title: "Test block"}
+	require.NoError(t, s.InsertBlock(block))
+
+	got, err := s.GetBlock("block-1")
+	require.NoError(t, err)
+	require.Equal(t, block.Title, got.Title)
+
+	require.NoError(t, s.DeleteBlock("block-1"))
+	_, err = s.GetBlock("block-1")
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+// TestSQLSessionInsertBlockUpsertsWithoutNativeClause covers InsertBlock's
+// fallback path for dialects (MSSQL) whose UpsertClause is empty: the first
+// InsertBlock should create the row, and a second InsertBlock with the same
+// ID should update it in place rather than failing on a duplicate key.
+func TestSQLSessionInsertBlockUpsertsWithoutNativeClause(t *testing.T) {
+	s := newTestSQLSessionWithDialect(t, mssqlDialect{})
+
+	require.NoError(t, s.InsertBlock(&model.Block{ID: "block-1", Title: "First"}))
+	got, err := s.GetBlock("block-1")
+	require.NoError(t, err)
+	require.Equal(t, "First", got.Title)
+
+	require.NoError(t, s.InsertBlock(&model.Block{ID: "block-1", Title: "Second"}))
+	got, err = s.GetBlock("block-1")
+	require.NoError(t, err)
+	require.Equal(t, "Second", got.Title)
+}
@@ -0,0 +1,222 @@
+package sqlstore
+
+import (
+	"fmt"
+	"strings"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"github.com/mattermost/focalboard/server/model"
+)
+
+// IntervalUnit is the unit of time a DateSub interval is expressed in.
+type IntervalUnit int
+
+const (
+	IntervalDay IntervalUnit = iota
+	IntervalMonth
+	IntervalYear
+)
+
+// Interval is a parsed "N day(s)/month(s)/year(s)" duration, as accepted
+// by durationSelector.
+type Interval struct {
+	Unit      IntervalUnit
+	Magnitude int
+}
+
+// ErrDialectUnsupported is returned by a Dialect method when the requested
+// operation has no safe rewrite for that dialect, rather than ever
+// returning SQL that looks correct but silently computes the wrong thing.
+type ErrDialectUnsupported struct {
+	Dialect   string
+	Operation string
+	Detail    string
+}
+
+func (e ErrDialectUnsupported) Error() string {
+	return fmt.Sprintf("%s is not supported by the %s dialect: %s", e.Operation, e.Dialect, e.Detail)
+}
+
+// Dialect captures every piece of SQL syntax that varies between the
+// database backends Focalboard supports. Each supported dbType has exactly
+// one Dialect implementation, selected once in New, so store methods never
+// need to branch on dbType themselves.
+type Dialect interface {
+	// QuoteIdent quotes an identifier (table or column name) the way this
+	// dialect expects, e.g. `field`, "field" or [field].
+	QuoteIdent(name string) string
+
+	// Placeholder renders the nth (1-indexed) bind parameter placeholder.
+	Placeholder(n int) string
+
+	// PlaceholderFormat returns the squirrel placeholder format this
+	// dialect's driver expects.
+	PlaceholderFormat() sq.PlaceholderFormat
+
+	// StringAgg renders an expression that concatenates col across a group,
+	// separated by delim. It returns an error when this dialect has no way
+	// to express the aggregation (e.g. pre-2017 MSSQL, where a correct
+	// STUFF/FOR XML PATH rewrite needs a GROUP BY-correlated subquery this
+	// signature can't express).
+	StringAgg(col, delim string) (string, error)
+
+	// Contains renders a boolean expression that is true when placeholder
+	// appears as a substring of col.
+	Contains(col, placeholder string) string
+
+	// DateSub renders the RFC3339 timestamp `interval` in the past,
+	// computed client-side so the result is always a literal value safe
+	// to bind as a query parameter, never a raw SQL fragment.
+	DateSub(interval Interval) string
+
+	// JSONExtract renders an expression that pulls path out of the JSON
+	// stored in col. path is a bare dot-separated key path (e.g. "a.b"),
+	// the same for every dialect; callers never need to know which
+	// underlying dbType they're talking to.
+	JSONExtract(col, path string) string
+
+	// UpsertClause renders the dialect-specific tail of an INSERT that
+	// should update conflictCols' row instead of failing when a row with
+	// the same key already exists.
+	UpsertClause(table string, conflictCols []string, updateCols []string) string
+}
+
+// dialectFor returns the Dialect implementation for a given dbType.
+func dialectFor(dbType string) (Dialect, error) {
+	switch dbType {
+	case model.MysqlDBType:
+		return mysqlDialect{}, nil
+	case model.PostgresDBType:
+		return postgresDialect{}, nil
+	case model.SqliteDBType:
+		return sqliteDialect{}, nil
+	case model.MssqlDBType:
+		return mssqlDialect{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported dbType for dialect: %s", dbType)
+	}
+}
+
+type mysqlDialect struct{}
+
+func (mysqlDialect) QuoteIdent(name string) string           { return "`" + name + "`" }
+func (mysqlDialect) Placeholder(int) string                  { return "?" }
+func (mysqlDialect) PlaceholderFormat() sq.PlaceholderFormat { return sq.Question }
+func (mysqlDialect) StringAgg(col, delim string) (string, error) {
+	return fmt.Sprintf("GROUP_CONCAT(%s SEPARATOR '%s')", col, delim), nil
+}
+func (d mysqlDialect) Contains(col, placeholder string) string {
+	return fmt.Sprintf("instr(%s, %s) > 0", col, placeholder)
+}
+func (mysqlDialect) DateSub(interval Interval) string { return dateSubClientSide(interval) }
+func (mysqlDialect) JSONExtract(col, path string) string {
+	return fmt.Sprintf("JSON_EXTRACT(%s, '$.%s')", col, path)
+}
+func (d mysqlDialect) UpsertClause(table string, conflictCols, updateCols []string) string {
+	sets := make([]string, len(updateCols))
+	for i, c := range updateCols {
+		sets[i] = fmt.Sprintf("%s = VALUES(%s)", c, c)
+	}
+	return "ON DUPLICATE KEY UPDATE " + joinComma(sets)
+}
+
+type postgresDialect struct{}
+
+func (postgresDialect) QuoteIdent(name string) string           { return "\"" + name + "\"" }
+func (postgresDialect) Placeholder(n int) string                { return fmt.Sprintf("$%v", n) }
+func (postgresDialect) PlaceholderFormat() sq.PlaceholderFormat { return sq.Dollar }
+func (postgresDialect) StringAgg(col, delim string) (string, error) {
+	return fmt.Sprintf("string_agg(%s, '%s')", col, delim), nil
+}
+func (d postgresDialect) Contains(col, placeholder string) string {
+	return fmt.Sprintf("position(%s in %s) > 0", placeholder, col)
+}
+func (postgresDialect) DateSub(interval Interval) string { return dateSubClientSide(interval) }
+func (postgresDialect) JSONExtract(col, path string) string {
+	return fmt.Sprintf("%s #>> '{%s}'", col, strings.ReplaceAll(path, ".", ","))
+}
+func (d postgresDialect) UpsertClause(table string, conflictCols, updateCols []string) string {
+	sets := make([]string, len(updateCols))
+	for i, c := range updateCols {
+		sets[i] = fmt.Sprintf("%s = EXCLUDED.%s", c, c)
+	}
+	return fmt.Sprintf("ON CONFLICT (%s) DO UPDATE SET %s", joinComma(conflictCols), joinComma(sets))
+}
+
+type sqliteDialect struct{}
+
+func (sqliteDialect) QuoteIdent(name string) string           { return "\"" + name + "\"" }
+func (sqliteDialect) Placeholder(n int) string                { return fmt.Sprintf("$%v", n) }
+func (sqliteDialect) PlaceholderFormat() sq.PlaceholderFormat { return sq.Dollar }
+func (sqliteDialect) StringAgg(col, delim string) (string, error) {
+	return fmt.Sprintf("group_concat(%s, '%s')", col, delim), nil
+}
+func (d sqliteDialect) Contains(col, placeholder string) string {
+	return fmt.Sprintf("instr(%s, %s) > 0", col, placeholder)
+}
+func (sqliteDialect) DateSub(interval Interval) string { return dateSubClientSide(interval) }
+func (sqliteDialect) JSONExtract(col, path string) string {
+	return fmt.Sprintf("json_extract(%s, '$.%s')", col, path)
+}
+func (d sqliteDialect) UpsertClause(table string, conflictCols, updateCols []string) string {
+	sets := make([]string, len(updateCols))
+	for i, c := range updateCols {
+		sets[i] = fmt.Sprintf("%s = excluded.%s", c, c)
+	}
+	return fmt.Sprintf("ON CONFLICT (%s) DO UPDATE SET %s", joinComma(conflictCols), joinComma(sets))
+}
+
+type mssqlDialect struct {
+	stringAggSupported bool
+}
+
+func (mssqlDialect) QuoteIdent(name string) string           { return "[" + name + "]" }
+func (mssqlDialect) Placeholder(n int) string                { return fmt.Sprintf("@p%v", n) }
+func (mssqlDialect) PlaceholderFormat() sq.PlaceholderFormat { return sq.AtP }
+func (d mssqlDialect) StringAgg(col, delim string) (string, error) {
+	if d.stringAggSupported {
+		return fmt.Sprintf("STRING_AGG(%s, '%s')", col, delim), nil
+	}
+	// A correct pre-2017 substitute needs a STUFF/FOR XML PATH subquery
+	// correlated back to the grouped rows (typically a correlated subquery
+	// keyed on the GROUP BY column), which this (col, delim)-only signature
+	// has no way to express. Returning an uncorrelated rewrite here would
+	// silently collapse to col's own value instead of aggregating across
+	// the group, so callers get an error instead of wrong data.
+	return "", ErrDialectUnsupported{
+		Dialect:   "mssql",
+		Operation: "StringAgg",
+		Detail:    "STRING_AGG requires SQL Server 2017 or later; no correlated STUFF/FOR XML PATH substitute is implemented",
+	}
+}
+func (d mssqlDialect) Contains(col, placeholder string) string {
+	return fmt.Sprintf("CHARINDEX(%s, %s) > 0", placeholder, col)
+}
+
+// DateSub computes the literal timestamp client-side, like every other
+// dialect, rather than returning a "DATEADD(...)" SQL fragment: callers
+// bind DateSub's return value as a query parameter, and a raw SQL
+// fragment bound that way would be passed to the database as a literal
+// string instead of being evaluated.
+func (mssqlDialect) DateSub(interval Interval) string { return dateSubClientSide(interval) }
+func (mssqlDialect) JSONExtract(col, path string) string {
+	return fmt.Sprintf("JSON_VALUE(%s, '$.%s')", col, path)
+}
+func (d mssqlDialect) UpsertClause(table string, conflictCols, updateCols []string) string {
+	// MSSQL has no native upsert clause, so this returns an empty clause;
+	// callers check for that and fall back to their own path (see
+	// sqlSession.InsertBlock, which tries an UPDATE and then an INSERT).
+	return ""
+}
+
+func joinComma(parts []string) string {
+	out := ""
+	for i, p := range parts {
+		if i > 0 {
+			out += ", "
+		}
+		out += p
+	}
+	return out
+}
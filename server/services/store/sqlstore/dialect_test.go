@@ -0,0 +1,92 @@
+package sqlstore
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/focalboard/server/model"
+)
+
+func TestDialectFor(t *testing.T) {
+	cases := []struct {
+		dbType string
+		want   Dialect
+	}{
+		{model.MysqlDBType, mysqlDialect{}},
+		{model.PostgresDBType, postgresDialect{}},
+		{model.SqliteDBType, sqliteDialect{}},
+		{model.MssqlDBType, mssqlDialect{}},
+	}
+	for _, c := range cases {
+		d, err := dialectFor(c.dbType)
+		require.NoError(t, err)
+		require.IsType(t, c.want, d)
+	}
+
+	_, err := dialectFor("notareal-db")
+	require.Error(t, err)
+}
+
+func TestMssqlDialect(t *testing.T) {
+	d := mssqlDialect{}
+	require.Equal(t, "[MyColumn]", d.QuoteIdent("MyColumn"))
+	require.Equal(t, "@p1", d.Placeholder(1))
+	require.Equal(t, "CHARINDEX(@p1, Title) > 0", d.Contains("Title", "@p1"))
+
+	_, err := d.StringAgg("Title", ",")
+	require.Error(t, err, "pre-2017 MSSQL has no safe StringAgg rewrite")
+	require.IsType(t, ErrDialectUnsupported{}, err)
+
+	d.stringAggSupported = true
+	agg, err := d.StringAgg("Title", ",")
+	require.NoError(t, err)
+	require.Equal(t, "STRING_AGG(Title, ',')", agg)
+}
+
+func TestJSONExtract(t *testing.T) {
+	// Every dialect takes the same bare dot-separated path ("a.b") and
+	// builds its own dbType-specific wrapper around it, so a caller never
+	// needs to know which dialect it's talking to.
+	cases := []struct {
+		dialect Dialect
+		want    string
+	}{
+		{mysqlDialect{}, "JSON_EXTRACT(fields, '$.a.b')"},
+		{postgresDialect{}, "fields #>> '{a,b}'"},
+		{sqliteDialect{}, "json_extract(fields, '$.a.b')"},
+		{mssqlDialect{}, "JSON_VALUE(fields, '$.a.b')"},
+	}
+	for _, c := range cases {
+		require.Equal(t, c.want, c.dialect.JSONExtract("fields", "a.b"),
+			"%T should accept the same bare dot-separated path as every other dialect", c.dialect)
+	}
+}
+
+func TestUpsertClause(t *testing.T) {
+	cases := []struct {
+		dialect Dialect
+		want    string
+	}{
+		{mysqlDialect{}, "ON DUPLICATE KEY UPDATE title = VALUES(title)"},
+		{postgresDialect{}, "ON CONFLICT (id) DO UPDATE SET title = EXCLUDED.title"},
+		{sqliteDialect{}, "ON CONFLICT (id) DO UPDATE SET title = excluded.title"},
+		{mssqlDialect{}, ""},
+	}
+	for _, c := range cases {
+		got := c.dialect.UpsertClause("blocks", []string{"id"}, []string{"title"})
+		require.Equal(t, c.want, got)
+	}
+}
+
+func TestParseInterval(t *testing.T) {
+	interval, err := parseInterval("7 day")
+	require.NoError(t, err)
+	require.Equal(t, Interval{Unit: IntervalDay, Magnitude: 7}, interval)
+
+	_, err = parseInterval("garbage")
+	require.Error(t, err)
+
+	_, err = parseInterval("")
+	require.Error(t, err)
+}
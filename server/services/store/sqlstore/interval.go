@@ -0,0 +1,60 @@
+package sqlstore
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidInterval is returned by parseInterval when its input isn't a
+// "<N> day(s)|month(s)|year(s)" string.
+type ErrInvalidInterval struct {
+	Interval string
+}
+
+func (e ErrInvalidInterval) Error() string {
+	return fmt.Sprintf("invalid duration interval: %q", e.Interval)
+}
+
+// parseInterval parses strings like "7 day", "1 month" or "2 years" into an
+// Interval. It replaces the previous durationSelector parsing, which called
+// os.Exit(2) on a malformed interval instead of returning an error.
+func parseInterval(interval string) (Interval, error) {
+	fields := strings.Fields(interval)
+	if len(fields) == 0 {
+		return Interval{}, ErrInvalidInterval{Interval: interval}
+	}
+
+	magnitude, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return Interval{}, ErrInvalidInterval{Interval: interval}
+	}
+
+	switch {
+	case strings.Contains(interval, "day"):
+		return Interval{Unit: IntervalDay, Magnitude: magnitude}, nil
+	case strings.Contains(interval, "month"):
+		return Interval{Unit: IntervalMonth, Magnitude: magnitude}, nil
+	case strings.Contains(interval, "year"):
+		return Interval{Unit: IntervalYear, Magnitude: magnitude}, nil
+	default:
+		return Interval{}, ErrInvalidInterval{Interval: interval}
+	}
+}
+
+// dateSubClientSide computes interval time units before now, in Go, for the
+// dialects that expect the caller to bind a literal timestamp rather than
+// push the subtraction down into SQL.
+func dateSubClientSide(interval Interval) string {
+	switch interval.Unit {
+	case IntervalDay:
+		return time.Now().AddDate(0, 0, -1*interval.Magnitude).Format(time.RFC3339)
+	case IntervalMonth:
+		return time.Now().AddDate(0, -1*interval.Magnitude, 0).Format(time.RFC3339)
+	case IntervalYear:
+		return time.Now().AddDate(-1*interval.Magnitude, 0, 0).Format(time.RFC3339)
+	default:
+		return time.Now().Format(time.RFC3339)
+	}
+}
@@ -0,0 +1,52 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	_ "github.com/denisenkom/go-mssqldb"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/mattermost-plugin-api/cluster"
+	"github.com/mattermost/mattermost-server/v6/shared/mlog"
+)
+
+// TestMssqlStoreConnects opens a store against a real MSSQL instance and
+// runs a handful of sanity checks. It is skipped unless FB_TEST_MSSQL_DSN
+// is set, since it requires a running SQL Server to connect to.
+func TestMssqlStoreConnects(t *testing.T) {
+	dsn := os.Getenv("FB_TEST_MSSQL_DSN")
+	if dsn == "" {
+		t.Skip("FB_TEST_MSSQL_DSN not set, skipping MSSQL store tests")
+	}
+
+	db, err := sql.Open("mssql", dsn)
+	require.NoError(t, err)
+	defer db.Close()
+
+	// IsPlugin/NewMutexFn are wired here, rather than left zero-valued, so
+	// Migrate runs the same cluster-mutex-guarded path a plugin deployment
+	// uses in production instead of only ever exercising standalone mode.
+	mutexAPI := &fakeMutexPluginAPI{}
+	s, err := New(Params{
+		DBType:           model.MssqlDBType,
+		ConnectionString: dsn,
+		TablePrefix:      "test_",
+		Logger:           mlog.CreateConsoleTestLogger(false, "error"),
+		DB:               db,
+		IsPlugin:         true,
+		NewMutexFn: func(name string) (*cluster.Mutex, error) {
+			return cluster.NewMutex(mutexAPI, name)
+		},
+	})
+	require.NoError(t, err)
+	defer func() { require.NoError(t, s.Shutdown()) }()
+
+	require.Equal(t, model.MssqlDBType, s.DBType())
+	dialect, ok := s.dialect.(mssqlDialect)
+	require.True(t, ok)
+	require.True(t, dialect.stringAggSupported, "test MSSQL instance is expected to be 2017+")
+}
@@ -0,0 +1,87 @@
+package sqlstore
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-api/cluster"
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/mattermost/mattermost-server/v6/plugin"
+)
+
+// fakeMutexPluginAPI backs a cluster.Mutex with an in-memory compare-and-set
+// instead of a real plugin.API/KV store, so NewMutexFn's serialization
+// guarantee can be tested without a running Mattermost server. Embedding
+// plugin.API satisfies cluster.NewMutex's parameter type while leaving
+// every method besides KVSetWithOptions unimplemented (and unused, since
+// that's the only one a Mutex calls).
+type fakeMutexPluginAPI struct {
+	plugin.API
+
+	mu    sync.Mutex
+	value []byte
+}
+
+func (f *fakeMutexPluginAPI) KVSetWithOptions(key string, value []byte, options model.PluginKVSetOptions) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if options.Atomic && !bytesEqual(f.value, options.OldValue) {
+		return false, nil
+	}
+	f.value = value
+	return true, nil
+}
+
+func bytesEqual(a, b []byte) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// TestNewMutexFnSerializesConcurrentHolders proves the mutex a MutexFactory
+// produces actually excludes concurrent holders. This is the guarantee
+// Migrate relies on NewMutexFn for in plugin mode, to serialize migrations
+// across multiple plugin instances racing to start up against the same
+// database. store_integration_test.go and mssql_test.go wire the same
+// fake API into NewMutexFn, but only against a real database; this test
+// checks the exclusion itself without one.
+func TestNewMutexFnSerializesConcurrentHolders(t *testing.T) {
+	api := &fakeMutexPluginAPI{}
+	var newMutexFn MutexFactory = func(name string) (*cluster.Mutex, error) {
+		return cluster.NewMutex(api, name)
+	}
+
+	counter := 0
+	const holders = 20
+	var wg sync.WaitGroup
+	wg.Add(holders)
+	for i := 0; i < holders; i++ {
+		go func() {
+			defer wg.Done()
+			m, err := newMutexFn("migrations")
+			require.NoError(t, err)
+
+			m.Lock()
+			defer m.Unlock()
+
+			// A non-atomic read-modify-write would lose increments if two
+			// holders were ever inside the critical section at once.
+			local := counter
+			time.Sleep(time.Millisecond)
+			counter = local + 1
+		}()
+	}
+	wg.Wait()
+
+	require.Equal(t, holders, counter, "mutex must serialize all holders for lost updates to be impossible")
+}
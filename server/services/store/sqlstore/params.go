@@ -0,0 +1,47 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/mattermost/mattermost-server/v6/plugin"
+	"github.com/mattermost/mattermost-server/v6/shared/mlog"
+)
+
+var errEmptyDBType = errors.New("dbType cannot be empty")
+var errEmptyConnectionString = errors.New("connection string cannot be empty")
+var errMissingDB = errors.New("DB cannot be nil")
+var errMissingLogger = errors.New("logger cannot be nil")
+
+// Params holds everything needed to construct a SQLStore via New.
+type Params struct {
+	DBType           string
+	ConnectionString string
+	// ReplicaDSNs holds the connection strings for read replicas. Order is
+	// preserved and used as the initial round-robin rotation order.
+	ReplicaDSNs  []string
+	TablePrefix  string
+	Logger       *mlog.Logger
+	DB           *sql.DB
+	IsPlugin     bool
+	IsSingleUser bool
+	NewMutexFn   MutexFactory
+	PluginAPI    *plugin.API
+}
+
+// CheckValid verifies that the params are usable by New.
+func (p Params) CheckValid() error {
+	if p.DBType == "" {
+		return errEmptyDBType
+	}
+	if p.ConnectionString == "" {
+		return errEmptyConnectionString
+	}
+	if p.DB == nil {
+		return errMissingDB
+	}
+	if p.Logger == nil {
+		return errMissingLogger
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/mattermost/mattermost-server/v6/shared/mlog"
+)
+
+// maxReplicaFailures is the number of consecutive health-check failures a
+// replica can accrue before it is pulled out of rotation.
+const maxReplicaFailures = 3
+
+// replicaHealthCheckInterval is how often the background goroutine pings
+// each replica to decide whether it should rejoin rotation.
+const replicaHealthCheckInterval = 15 * time.Second
+
+type contextKey string
+
+// ctxKeyForcePrimary, when present on a context, forces reads to go against
+// the primary DB instead of a replica. Callers that just performed a write
+// and need read-your-writes semantics should wrap their context with
+// ForcePrimary before issuing the follow-up read.
+const ctxKeyForcePrimary contextKey = "force_primary"
+
+// ForcePrimary returns a context that routes any read performed with it
+// to the primary database instead of a replica.
+func ForcePrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, ctxKeyForcePrimary, true)
+}
+
+func forcesPrimary(ctx context.Context) bool {
+	v, _ := ctx.Value(ctxKeyForcePrimary).(bool)
+	return v
+}
+
+// replica wraps a single read-replica connection along with the health
+// bookkeeping needed to exclude it from rotation when it starts failing.
+type replica struct {
+	db          *sql.DB
+	dsn         string
+	consecFails int32
+	healthy     int32 // 1 = healthy, 0 = unhealthy; accessed atomically
+}
+
+func newReplica(db *sql.DB, dsn string) *replica {
+	return &replica{db: db, dsn: dsn, healthy: 1}
+}
+
+func (r *replica) isHealthy() bool {
+	return atomic.LoadInt32(&r.healthy) == 1
+}
+
+func (r *replica) recordResult(err error) {
+	if err == nil {
+		atomic.StoreInt32(&r.consecFails, 0)
+		atomic.StoreInt32(&r.healthy, 1)
+		return
+	}
+	if atomic.AddInt32(&r.consecFails, 1) >= maxReplicaFailures {
+		atomic.StoreInt32(&r.healthy, 0)
+	}
+}
+
+// markInitiallyUnhealthy excludes the replica from rotation immediately,
+// without waiting for maxReplicaFailures consecutive pings. It's used when
+// a replica is already unreachable at store construction time: the store
+// should still start up, with the replica excluded until monitor's
+// periodic pings find it healthy again.
+func (r *replica) markInitiallyUnhealthy() {
+	atomic.StoreInt32(&r.consecFails, maxReplicaFailures)
+	atomic.StoreInt32(&r.healthy, 0)
+}
+
+// replicaPool round-robins reads across a set of healthy replicas, falling
+// back to the primary when none are available.
+type replicaPool struct {
+	replicas []*replica
+	next     uint32
+
+	stopOnce sync.Once
+	stopCh   chan struct{}
+}
+
+func newReplicaPool(replicas []*replica) *replicaPool {
+	return &replicaPool{
+		replicas: replicas,
+		stopCh:   make(chan struct{}),
+	}
+}
+
+// pick returns the next healthy replica in rotation, or nil if none are
+// currently healthy.
+func (p *replicaPool) pick() *replica {
+	if len(p.replicas) == 0 {
+		return nil
+	}
+	for i := 0; i < len(p.replicas); i++ {
+		idx := atomic.AddUint32(&p.next, 1) % uint32(len(p.replicas))
+		if candidate := p.replicas[idx]; candidate.isHealthy() {
+			return candidate
+		}
+	}
+	return nil
+}
+
+// monitor runs until stop() is called, periodically pinging every replica:
+// one that fails maxReplicaFailures pings in a row leaves rotation, and an
+// unhealthy one rejoins on its next successful ping.
+func (p *replicaPool) monitor(logger *mlog.Logger) {
+	ticker := time.NewTicker(replicaHealthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.stopCh:
+			return
+		case <-ticker.C:
+			for _, r := range p.replicas {
+				err := r.db.Ping()
+				wasHealthy := r.isHealthy()
+				r.recordResult(err)
+				if err != nil && wasHealthy {
+					logger.Warn("replica marked unhealthy", mlog.Err(err))
+				} else if err == nil && !wasHealthy {
+					logger.Info("replica recovered, rejoining rotation")
+				}
+			}
+		}
+	}
+}
+
+func (p *replicaPool) stop() {
+	p.stopOnce.Do(func() {
+		close(p.stopCh)
+	})
+}
+
+func (p *replicaPool) close() error {
+	p.stop()
+	var firstErr error
+	for _, r := range p.replicas {
+		if err := r.db.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
@@ -0,0 +1,161 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-server/v6/shared/mlog"
+)
+
+func TestReplicaRecordResult(t *testing.T) {
+	r := newReplica(nil, "dsn")
+	require.True(t, r.isHealthy())
+
+	failErr := errors.New("connection refused")
+	for i := 0; i < maxReplicaFailures; i++ {
+		r.recordResult(failErr)
+	}
+	require.False(t, r.isHealthy(), "replica should be excluded after maxReplicaFailures consecutive errors")
+
+	r.recordResult(nil)
+	require.True(t, r.isHealthy(), "a single successful ping should bring the replica back into rotation")
+}
+
+func TestReplicaMarkInitiallyUnhealthy(t *testing.T) {
+	r := newReplica(nil, "dsn")
+	r.markInitiallyUnhealthy()
+	require.False(t, r.isHealthy())
+}
+
+func TestReplicaPoolPickExcludesUnhealthy(t *testing.T) {
+	healthy := newReplica(nil, "healthy")
+	unhealthy := newReplica(nil, "unhealthy")
+	unhealthy.markInitiallyUnhealthy()
+
+	pool := newReplicaPool([]*replica{healthy, unhealthy})
+	for i := 0; i < 5; i++ {
+		require.Same(t, healthy, pool.pick())
+	}
+}
+
+func TestReplicaPoolPickReturnsNilWhenAllUnhealthy(t *testing.T) {
+	a := newReplica(nil, "a")
+	a.markInitiallyUnhealthy()
+	b := newReplica(nil, "b")
+	b.markInitiallyUnhealthy()
+
+	pool := newReplicaPool([]*replica{a, b})
+	require.Nil(t, pool.pick())
+}
+
+func TestReadHandleRoutesToHealthyReplica(t *testing.T) {
+	primaryDB := &sql.DB{}
+	replicaDB := &sql.DB{}
+
+	s := &SQLStore{
+		db:       primaryDB,
+		replicas: newReplicaPool([]*replica{newReplica(replicaDB, "replica")}),
+	}
+
+	require.Same(t, replicaDB, s.readHandle(context.Background()))
+}
+
+func TestReadHandleFallsBackToPrimary(t *testing.T) {
+	primaryDB := &sql.DB{}
+
+	s := &SQLStore{db: primaryDB}
+	require.Same(t, primaryDB, s.readHandle(context.Background()), "no replicas configured")
+
+	unhealthy := newReplica(&sql.DB{}, "replica")
+	unhealthy.markInitiallyUnhealthy()
+	s.replicas = newReplicaPool([]*replica{unhealthy})
+	require.Same(t, primaryDB, s.readHandle(context.Background()), "all replicas unhealthy")
+}
+
+func TestReadHandleHonorsForcePrimary(t *testing.T) {
+	primaryDB := &sql.DB{}
+	replicaDB := &sql.DB{}
+
+	s := &SQLStore{
+		db:       primaryDB,
+		replicas: newReplicaPool([]*replica{newReplica(replicaDB, "replica")}),
+	}
+
+	ctx := ForcePrimary(context.Background())
+	require.Same(t, primaryDB, s.readHandle(ctx))
+}
+
+// fakeReplicaConn is a no-op driver.Conn that counts how many times it's
+// closed, so tests can confirm openReplicas doesn't leak pools it already
+// opened when a later replica in the list fails to open.
+type fakeReplicaConn struct {
+	closed *int32
+}
+
+func (c *fakeReplicaConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("fakeReplicaConn: Prepare not implemented")
+}
+func (c *fakeReplicaConn) Close() error { atomic.AddInt32(c.closed, 1); return nil }
+func (c *fakeReplicaConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("fakeReplicaConn: Begin not implemented")
+}
+
+// fakeReplicaDriver fails to open a connector for any dsn in failDSNs,
+// implementing driver.DriverContext so that failure surfaces from
+// sql.Open itself, the same way a malformed replica DSN would.
+type fakeReplicaDriver struct {
+	failDSNs map[string]bool
+	closed   *int32
+}
+
+func (d fakeReplicaDriver) Open(dsn string) (driver.Conn, error) {
+	return nil, errors.New("fakeReplicaDriver: Open not implemented, use OpenConnector")
+}
+
+func (d fakeReplicaDriver) OpenConnector(dsn string) (driver.Connector, error) {
+	if d.failDSNs[dsn] {
+		return nil, fmt.Errorf("fakeReplicaDriver: refusing to open %q", dsn)
+	}
+	return fakeReplicaConnector{driver: d, closed: d.closed}, nil
+}
+
+type fakeReplicaConnector struct {
+	driver driver.Driver
+	closed *int32
+}
+
+func (c fakeReplicaConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	return &fakeReplicaConn{closed: c.closed}, nil
+}
+func (c fakeReplicaConnector) Driver() driver.Driver { return c.driver }
+
+func TestOpenReplicasClosesAlreadyOpenedPoolsOnLaterFailure(t *testing.T) {
+	var closed int32
+	driverName := "fakeReplicaDriver-" + t.Name()
+	sql.Register(driverName, fakeReplicaDriver{
+		failDSNs: map[string]bool{"replica-bad": true},
+		closed:   &closed,
+	})
+
+	params := Params{
+		DBType:      driverName,
+		ReplicaDSNs: []string{"replica-good", "replica-bad"},
+		Logger:      mlog.CreateConsoleLogger(false, "info"),
+	}
+
+	pool, err := openReplicas(params)
+	require.Error(t, err, "replica-bad should fail to open")
+	require.Nil(t, pool)
+
+	// replica-good's Ping call above opened one pooled connection; it must
+	// have been closed rather than leaked once replica-bad failed to open.
+	require.Equal(t, int32(1), atomic.LoadInt32(&closed),
+		"openReplicas must close pools it already opened before a later replica fails")
+}
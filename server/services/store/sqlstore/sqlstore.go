@@ -1,13 +1,11 @@
 package sqlstore
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"net/url"
-	"os"
 	"strconv"
-	"strings"
-	"time"
 
 	"github.com/mattermost/mattermost-server/v6/plugin"
 
@@ -24,6 +22,7 @@ import (
 // SQLStore is a SQL database.
 type SQLStore struct {
 	db               *sql.DB
+	replicas         *replicaPool
 	dbType           string
 	tablePrefix      string
 	connectionString string
@@ -33,6 +32,10 @@ type SQLStore struct {
 	NewMutexFn       MutexFactory
 	pluginAPI        *plugin.API
 	isBinaryParam    bool
+	// dialect holds the dbType-specific SQL generation for this store,
+	// selected once in New. Store methods should go through it instead of
+	// branching on dbType themselves.
+	dialect Dialect
 }
 
 // MutexFactory is used by the store in plugin mode to generate
@@ -47,7 +50,6 @@ func New(params Params) (*SQLStore, error) {
 
 	params.Logger.Info("connectDatabase", mlog.String("dbType", params.DBType))
 	store := &SQLStore{
-		// TODO: add replica DB support too.
 		db:               params.DB,
 		dbType:           params.DBType,
 		tablePrefix:      params.TablePrefix,
@@ -59,24 +61,93 @@ func New(params Params) (*SQLStore, error) {
 		pluginAPI:        params.PluginAPI,
 	}
 
-	var err error
+	dialect, err := dialectFor(store.dbType)
+	if err != nil {
+		params.Logger.Error(`Unsupported dbType`, mlog.Err(err))
+		return nil, err
+	}
+	if d, ok := dialect.(mssqlDialect); ok {
+		d.stringAggSupported = store.computeMssqlStringAggSupport()
+		dialect = d
+	}
+	store.dialect = dialect
+
+	replicas, err := openReplicas(params)
+	if err != nil {
+		params.Logger.Error(`Cannot open replica databases`, mlog.Err(err))
+		return nil, err
+	}
+	store.replicas = replicas
+	if store.replicas != nil {
+		go store.replicas.monitor(store.logger)
+	}
+
 	store.isBinaryParam, err = store.computeBinaryParam()
 	if err != nil {
 		params.Logger.Error(`Cannot compute binary parameter`, mlog.Err(err))
+		store.closeReplicas()
 		return nil, err
 	}
 
+	// Migrations must only ever run against the primary; replicas may still
+	// be catching up at this point.
 	err = store.Migrate()
 	if err != nil {
 		params.Logger.Error(`Table creation / migration failed`, mlog.Err(err))
-
+		store.closeReplicas()
 		return nil, err
 	}
 	return store, nil
 }
 
+// closeReplicas stops the replica monitor goroutine and closes every
+// replica connection pool. It's used by New to unwind replicas that were
+// already opened once a later construction step fails, so a failed New
+// never leaks the monitor goroutine or the replicas' *sql.DB pools.
+func (s *SQLStore) closeReplicas() {
+	if s.replicas == nil {
+		return
+	}
+	if err := s.replicas.close(); err != nil {
+		s.logger.Error("error closing replica connections", mlog.Err(err))
+	}
+}
+
+// openReplicas opens a connection pool for each configured read replica.
+// It returns a nil pool when no replicas are configured, so callers always
+// fall back to the primary. A replica that's unreachable at startup does
+// not fail store construction: it's added to the pool already marked
+// unhealthy, and monitor's periodic pings will bring it into rotation once
+// it recovers. Only a malformed DSN (sql.Open itself failing) is treated
+// as a hard error, since that's a configuration mistake rather than a
+// transient outage.
+func openReplicas(params Params) (*replicaPool, error) {
+	if len(params.ReplicaDSNs) == 0 {
+		return nil, nil
+	}
+
+	replicas := make([]*replica, 0, len(params.ReplicaDSNs))
+	for _, dsn := range params.ReplicaDSNs {
+		db, err := sql.Open(params.DBType, dsn)
+		if err != nil {
+			newReplicaPool(replicas).close()
+			return nil, err
+		}
+
+		r := newReplica(db, dsn)
+		if err := db.Ping(); err != nil {
+			params.Logger.Warn("replica unreachable at startup, excluding from rotation until it recovers",
+				mlog.Err(err))
+			r.markInitiallyUnhealthy()
+		}
+		replicas = append(replicas, r)
+	}
+	return newReplicaPool(replicas), nil
+}
+
 // computeBinaryParam returns whether the data source uses binary_parameters
-// when using Postgres.
+// when using Postgres. It short-circuits for every other dbType, MSSQL
+// included, since binary_parameters is a Postgres-only connection option.
 func (s *SQLStore) computeBinaryParam() (bool, error) {
 	if s.dbType != model.PostgresDBType {
 		return false, nil
@@ -89,82 +160,133 @@ func (s *SQLStore) computeBinaryParam() (bool, error) {
 	return url.Query().Get("binary_parameters") == "yes", nil
 }
 
+// computeMssqlStringAggSupport reports whether the connected SQL Server
+// instance is new enough (2017+) to provide STRING_AGG. On older instances
+// mssqlDialect.StringAgg returns ErrDialectUnsupported; no STUFF(... FOR
+// XML PATH) substitute is generated.
+func (s *SQLStore) computeMssqlStringAggSupport() bool {
+	var productVersion string
+	row := s.db.QueryRow("SELECT SERVERPROPERTY('ProductMajorVersion')")
+	if err := row.Scan(&productVersion); err != nil {
+		s.logger.Warn("could not determine MSSQL product version, assuming STRING_AGG is unsupported", mlog.Err(err))
+		return false
+	}
+
+	majorVersion, err := strconv.Atoi(productVersion)
+	if err != nil {
+		s.logger.Warn("could not parse MSSQL product version, assuming STRING_AGG is unsupported", mlog.String("version", productVersion))
+		return false
+	}
+
+	// SQL Server 2017 is major version 14.
+	return majorVersion >= 14
+}
+
 // Shutdown close the connection with the store.
 func (s *SQLStore) Shutdown() error {
+	s.closeReplicas()
 	return s.db.Close()
 }
 
-// DBHandle returns the raw sql.DB handle.
+// DBHandle returns the raw sql.DB handle for the primary database.
 // It is used by the mattermostauthlayer to run their own
 // raw SQL queries.
 func (s *SQLStore) DBHandle() *sql.DB {
 	return s.db
 }
 
+// readHandle returns the DB handle that a read-only query should use: a
+// healthy replica when one is available, or the primary otherwise. Callers
+// that need read-your-writes semantics (they just wrote through the
+// primary and must observe that write) should pass a context wrapped with
+// ForcePrimary.
+func (s *SQLStore) readHandle(ctx context.Context) *sql.DB {
+	if s.replicas == nil || forcesPrimary(ctx) {
+		return s.db
+	}
+	if r := s.replicas.pick(); r != nil {
+		return r.db
+	}
+	return s.db
+}
+
 // DBType returns the DB driver used for the store.
 func (s *SQLStore) DBType() string {
 	return s.dbType
 }
 
 func (s *SQLStore) getQueryBuilder(db sq.BaseRunner) sq.StatementBuilderType {
-	builder := sq.StatementBuilder
-	if s.dbType == model.PostgresDBType || s.dbType == model.SqliteDBType {
-		builder = builder.PlaceholderFormat(sq.Dollar)
-	}
-
+	builder := sq.StatementBuilder.PlaceholderFormat(s.dialect.PlaceholderFormat())
 	return builder.RunWith(db)
 }
 
-func (s *SQLStore) escapeField(fieldName string) string {
-	if s.dbType == model.MysqlDBType {
-		return "`" + fieldName + "`"
+// getReadQueryBuilder is like getQueryBuilder, except that when db is the
+// store's primary handle (i.e. the caller isn't already inside a
+// transaction) it is swapped for a read replica when one is healthy and
+// available. Store methods that only ever SELECT (counts, search, get-by-id)
+// should build their query through this instead of getQueryBuilder.
+func (s *SQLStore) getReadQueryBuilder(ctx context.Context, db sq.BaseRunner) sq.StatementBuilderType {
+	if db == sq.BaseRunner(s.db) {
+		db = s.readHandle(ctx)
 	}
-	if s.dbType == model.PostgresDBType || s.dbType == model.SqliteDBType {
-		return "\"" + fieldName + "\""
+	return s.getQueryBuilder(db)
+}
+
+// blockCount returns the number of blocks with the given parent ID. It's a
+// plain read with no read-your-writes requirement, so it's built through
+// getReadQueryBuilder and round-robins across healthy replicas; callers
+// that just wrote a block and need to see it immediately should pass a
+// context wrapped with ForcePrimary instead.
+func (s *SQLStore) blockCount(ctx context.Context, parentID string) (int, error) {
+	query := s.getReadQueryBuilder(ctx, s.db).
+		Select("COUNT(*)").
+		From(s.tablePrefix + "blocks").
+		Where(sq.Eq{"parent_id": parentID})
+
+	var count int
+	if err := query.QueryRow().Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count blocks for parent %s: %w", parentID, err)
 	}
-	return fieldName
+	return count, nil
 }
 
+func (s *SQLStore) escapeField(fieldName string) string {
+	return s.dialect.QuoteIdent(fieldName)
+}
+
+// durationSelector returns the RFC3339 timestamp `interval` before now,
+// e.g. durationSelector("7 day"). Its signature stays source-compatible
+// with every existing board/block/subscription store call site: an
+// interval that fails to parse is logged and falls back to "now" instead
+// of exiting the process (the previous behavior) or adding a second
+// return value those call sites don't expect.
 func (s *SQLStore) durationSelector(interval string) string {
-	intervalMagnitudeString := strings.Fields(interval)[0]
-	intervalMagnitude, err := strconv.Atoi(intervalMagnitudeString)
+	parsed, err := parseInterval(interval)
 	if err != nil {
-		// handle error
-		os.Exit(2)
-	}
-	if strings.Contains(interval, "day") {
-		return time.Now().AddDate(0, 0, -1*intervalMagnitude).Format(time.RFC3339)
+		s.logger.Error("invalid duration interval, defaulting to now", mlog.String("interval", interval), mlog.Err(err))
+		return dateSubClientSide(Interval{})
 	}
-	if strings.Contains(interval, "month") {
-		return time.Now().AddDate(0, -1*intervalMagnitude, 0).Format(time.RFC3339)
-	}
-	if strings.Contains(interval, "year") {
-		return time.Now().AddDate(-1*intervalMagnitude, 0, 0).Format(time.RFC3339)
-	}
-	return time.Now().Format(time.RFC3339)
+	return s.dialect.DateSub(parsed)
 }
 
+// concatenationSelector returns an expression that concatenates field
+// across a group, separated by delimiter. Its signature stays source-
+// compatible with every existing call site: when the dialect has no safe
+// way to express the aggregation, the error is logged and field itself is
+// returned unaggregated rather than adding a second return value those
+// call sites don't expect.
 func (s *SQLStore) concatenationSelector(field string, delimiter string) string {
-	if s.dbType == model.SqliteDBType {
-		return fmt.Sprintf("group_concat(%s)", field)
-	}
-	if s.dbType == model.PostgresDBType {
-		return fmt.Sprintf("string_agg(%s, '%s')", field, delimiter)
-	}
-	if s.dbType == model.MysqlDBType {
-		return fmt.Sprintf("GROUP_CONCAT(%s SEPARATOR '%s')", field, delimiter)
+	agg, err := s.dialect.StringAgg(field, delimiter)
+	if err != nil {
+		s.logger.Error("dialect cannot express string aggregation, falling back to the field itself",
+			mlog.String("field", field), mlog.Err(err))
+		return s.escapeField(field)
 	}
-	return ""
+	return agg
 }
 
 func (s *SQLStore) elementInColumn(parameterCount int, column string) string {
-	if s.dbType == model.SqliteDBType || s.dbType == model.MysqlDBType {
-		return fmt.Sprintf("instr(%s, %s) > 0", column, s.parameterPlaceholder(parameterCount))
-	}
-	if s.dbType == model.PostgresDBType {
-		return fmt.Sprintf("position(%s in %s) > 0", s.parameterPlaceholder(parameterCount), column)
-	}
-	return ""
+	return s.dialect.Contains(column, s.parameterPlaceholder(parameterCount))
 }
 
 func (s *SQLStore) getLicense(db sq.BaseRunner) *mmModel.License {
@@ -172,13 +294,7 @@ func (s *SQLStore) getLicense(db sq.BaseRunner) *mmModel.License {
 }
 
 func (s *SQLStore) parameterPlaceholder(count int) string {
-	if s.dbType == model.PostgresDBType || s.dbType == model.SqliteDBType {
-		return fmt.Sprintf("$%v", count)
-	}
-	if s.dbType == model.MysqlDBType {
-		return "?"
-	}
-	return ""
+	return s.dialect.Placeholder(count)
 }
 
 func (s *SQLStore) getCloudLimits(db sq.BaseRunner) (*mmModel.ProductLimits, error) {
@@ -0,0 +1,41 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/mattermost/focalboard/server/services/store"
+	"github.com/mattermost/focalboard/server/services/store/sqlstore/storetest"
+	"github.com/mattermost/mattermost-plugin-api/cluster"
+	"github.com/mattermost/mattermost-server/v6/shared/mlog"
+)
+
+// TestStoreAcrossBackends runs the shared golden-path suite against
+// disposable MySQL and Postgres containers, catching dialect-specific bugs
+// in things like concatenationSelector and elementInColumn that the
+// default SQLite-only test run can't see.
+func TestStoreAcrossBackends(t *testing.T) {
+	// IsPlugin/NewMutexFn are wired here, rather than left zero-valued, so
+	// Migrate runs the same cluster-mutex-guarded path a plugin deployment
+	// uses in production instead of only ever exercising standalone mode.
+	mutexAPI := &fakeMutexPluginAPI{}
+
+	storetest.Run(t, func(dbType, connectionString string) (store.Store, error) {
+		db, err := sql.Open(dbType, connectionString)
+		if err != nil {
+			return nil, err
+		}
+
+		return New(Params{
+			DBType:           dbType,
+			ConnectionString: connectionString,
+			TablePrefix:      "test_",
+			Logger:           mlog.CreateConsoleTestLogger(false, "error"),
+			DB:               db,
+			IsPlugin:         true,
+			NewMutexFn: func(name string) (*cluster.Mutex, error) {
+				return cluster.NewMutex(mutexAPI, name)
+			},
+		})
+	})
+}
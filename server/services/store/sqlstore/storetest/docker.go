@@ -0,0 +1,142 @@
+// Package storetest provides a disposable-container test harness for the
+// sqlstore package. It spins up MySQL and Postgres (and, eventually,
+// MSSQL) containers via testcontainers-go so the store test suite can run
+// against every supported dialect instead of just SQLite.
+package storetest
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+// containerDB is a running database container along with the DSN needed to
+// connect to it and a func to tear it down.
+type containerDB struct {
+	dsn     string
+	cleanup func()
+}
+
+const containerStartTimeout = 2 * time.Minute
+
+// startMySQLContainer starts a disposable MySQL container and returns its
+// DSN. If FB_TEST_MYSQL_DSN is set, that DSN is reused instead and no
+// container is started, so CI can point at a long-lived instance.
+func startMySQLContainer(t *testing.T) containerDB {
+	t.Helper()
+
+	if dsn := os.Getenv("FB_TEST_MYSQL_DSN"); dsn != "" {
+		return containerDB{dsn: dsn, cleanup: func() {}}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), containerStartTimeout)
+	defer cancel()
+
+	const (
+		user     = "focalboard"
+		password = "focalboard"
+		dbName   = "focalboard_test"
+	)
+
+	req := testcontainers.ContainerRequest{
+		Image:        "mysql:8.0",
+		ExposedPorts: []string{"3306/tcp"},
+		Env: map[string]string{
+			"MYSQL_ROOT_PASSWORD": password,
+			"MYSQL_USER":          user,
+			"MYSQL_PASSWORD":      password,
+			"MYSQL_DATABASE":      dbName,
+		},
+		WaitingFor: wait.ForLog("ready for connections").WithOccurrence(2),
+	}
+
+	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: req,
+		Started:          true,
+	})
+	if err != nil {
+		t.Fatalf("failed to start mysql container: %v", err)
+	}
+
+	host, err := container.Host(ctx)
+	if err != nil {
+		t.Fatalf("failed to get mysql container host: %v", err)
+	}
+	port, err := container.MappedPort(ctx, "3306")
+	if err != nil {
+		t.Fatalf("failed to get mysql container port: %v", err)
+	}
+
+	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=true", user, password, host, port.Port(), dbName)
+	return containerDB{
+		dsn: dsn,
+		cleanup: func() {
+			if err := container.Terminate(context.Background()); err != nil {
+				t.Logf("failed to terminate mysql container: %v", err)
+			}
+		},
+	}
+}
+
+// startPostgresContainer starts a disposable Postgres container and returns
+// its DSN. If FB_TEST_POSTGRES_DSN is set, that DSN is reused instead and
+// no container is started, so CI can point at a long-lived instance.
+func startPostgresContainer(t *testing.T) containerDB {
+	t.Helper()
+
+	if dsn := os.Getenv("FB_TEST_POSTGRES_DSN"); dsn != "" {
+		return containerDB{dsn: dsn, cleanup: func() {}}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), containerStartTimeout)
+	defer cancel()
+
+	const (
+		user     = "focalboard"
+		password = "focalboard"
+		dbName   = "focalboard_test"
+	)
+
+	req := testcontainers.ContainerRequest{
+		Image:        "postgres:13-alpine",
+		ExposedPorts: []string{"5432/tcp"},
+		Env: map[string]string{
+			"POSTGRES_USER":     user,
+			"POSTGRES_PASSWORD": password,
+			"POSTGRES_DB":       dbName,
+		},
+		WaitingFor: wait.ForLog("database system is ready to accept connections").WithOccurrence(2),
+	}
+
+	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: req,
+		Started:          true,
+	})
+	if err != nil {
+		t.Fatalf("failed to start postgres container: %v", err)
+	}
+
+	host, err := container.Host(ctx)
+	if err != nil {
+		t.Fatalf("failed to get postgres container host: %v", err)
+	}
+	port, err := container.MappedPort(ctx, "5432")
+	if err != nil {
+		t.Fatalf("failed to get postgres container port: %v", err)
+	}
+
+	dsn := fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=disable", user, password, host, port.Port(), dbName)
+	return containerDB{
+		dsn: dsn,
+		cleanup: func() {
+			if err := container.Terminate(context.Background()); err != nil {
+				t.Logf("failed to terminate postgres container: %v", err)
+			}
+		},
+	}
+}
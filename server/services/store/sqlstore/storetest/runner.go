@@ -0,0 +1,281 @@
+package storetest
+
+import (
+	"database/sql"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/store"
+)
+
+// shutdowner is the subset of store.Store this harness depends on directly,
+// kept narrow so RunStoreTests doesn't need to track every method the full
+// interface grows over time.
+type shutdowner interface {
+	Shutdown() error
+}
+
+// sqlHandleStore is implemented by every sqlstore-backed store.Store (it's
+// the same DBHandle method mattermostauthlayer already relies on). The
+// golden-path tests below need the raw *sql.DB to actually exercise
+// transactions and concurrent writes, rather than just asserting a store
+// opened without error.
+type sqlHandleStore interface {
+	DBHandle() *sql.DB
+}
+
+// migrationCheckTable is a table private to this test harness, separate
+// from anything Migrate creates, so these tests can freely insert/update
+// rows without coupling to Focalboard's actual schema.
+const migrationCheckTable = "storetest_golden_path_check"
+
+// Run executes the full store test suite against disposable MySQL and
+// Postgres containers; MSSQL is not among the backends run here. It is
+// skipped under `go test -short`, so contributors without Docker installed
+// can still run the regular unit tests.
+func Run(t *testing.T, newStore func(dbType, connectionString string) (store.Store, error)) {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("skipping container-backed store tests in -short mode")
+	}
+
+	backends := []struct {
+		name   string
+		dbType string
+		start  func(t *testing.T) containerDB
+	}{
+		{"mysql", model.MysqlDBType, startMySQLContainer},
+		{"postgres", model.PostgresDBType, startPostgresContainer},
+	}
+
+	for _, b := range backends {
+		b := b
+		t.Run(b.name, func(t *testing.T) {
+			db := b.start(t)
+			defer db.cleanup()
+
+			RunStoreTests(t, b.dbType, func() store.Store {
+				s, err := newStore(b.dbType, db.dsn)
+				if err != nil {
+					t.Fatalf("failed to open %s store: %v", b.name, err)
+				}
+				return s
+			})
+		})
+	}
+}
+
+// RunStoreTests runs the golden-path store test suite — migrations,
+// transactions, and concurrent writes — against whatever factory produces.
+// Backend-specific test files call this instead of duplicating container
+// setup and teardown.
+func RunStoreTests(t *testing.T, dbType string, factory func() store.Store) {
+	t.Helper()
+
+	t.Run("Migrations", func(t *testing.T) {
+		testMigrations(t, dbType, factory)
+	})
+	t.Run("Transactions", func(t *testing.T) {
+		testTransactions(t, dbType, factory)
+	})
+	t.Run("ConcurrentWrites", func(t *testing.T) {
+		testConcurrentWrites(t, dbType, factory)
+	})
+}
+
+// placeholder renders the nth (1-indexed) bind parameter the way dbType's
+// driver expects it.
+func placeholder(dbType string, n int) string {
+	if dbType == model.MysqlDBType {
+		return "?"
+	}
+	return fmt.Sprintf("$%d", n)
+}
+
+func sqlHandleOf(t *testing.T, s store.Store) *sql.DB {
+	t.Helper()
+	h, ok := s.(sqlHandleStore)
+	if !ok {
+		t.Skip("backend does not expose a *sql.DB handle; skipping SQL-specific golden-path test")
+	}
+	return h.DBHandle()
+}
+
+func createCheckTable(db *sql.DB) error {
+	_, err := db.Exec(fmt.Sprintf(
+		"CREATE TABLE IF NOT EXISTS %s (id INT PRIMARY KEY, value INT NOT NULL)", migrationCheckTable))
+	return err
+}
+
+// testMigrations checks that reopening a store against a database that's
+// already been migrated (the normal case on every process restart) is
+// idempotent: it must not error and must not clobber data that was already
+// there.
+func testMigrations(t *testing.T, dbType string, factory func() store.Store) {
+	s := factory()
+	db := sqlHandleOf(t, s)
+
+	if err := createCheckTable(db); err != nil {
+		t.Fatalf("failed to create check table: %v", err)
+	}
+	insert := fmt.Sprintf("INSERT INTO %s (id, value) VALUES (%s, %s)",
+		migrationCheckTable, placeholder(dbType, 1), placeholder(dbType, 2))
+	if _, err := db.Exec(insert, 1, 100); err != nil {
+		t.Fatalf("failed to seed check table: %v", err)
+	}
+	shutdown(t, s)
+
+	// Re-running migrations (opening a second store against the same
+	// database) must be idempotent and must not disturb existing data.
+	s2 := factory()
+	defer shutdown(t, s2)
+	db2 := sqlHandleOf(t, s2)
+
+	if err := createCheckTable(db2); err != nil {
+		t.Fatalf("failed to re-create check table after re-migration: %v", err)
+	}
+
+	var value int
+	query := fmt.Sprintf("SELECT value FROM %s WHERE id = %s", migrationCheckTable, placeholder(dbType, 1))
+	if err := db2.QueryRow(query, 1).Scan(&value); err != nil {
+		t.Fatalf("failed to read back row surviving re-migration: %v", err)
+	}
+	if value != 100 {
+		t.Fatalf("row mutated by re-migration: want 100, got %d", value)
+	}
+}
+
+// testTransactions checks that a rolled-back write never becomes visible
+// and a committed write does.
+func testTransactions(t *testing.T, dbType string, factory func() store.Store) {
+	s := factory()
+	defer shutdown(t, s)
+	db := sqlHandleOf(t, s)
+
+	if err := createCheckTable(db); err != nil {
+		t.Fatalf("failed to create check table: %v", err)
+	}
+	insert := fmt.Sprintf("INSERT INTO %s (id, value) VALUES (%s, %s)",
+		migrationCheckTable, placeholder(dbType, 1), placeholder(dbType, 2))
+	countByID := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE id = %s", migrationCheckTable, placeholder(dbType, 1))
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatalf("failed to begin transaction: %v", err)
+	}
+	if _, err := tx.Exec(insert, 2, 1); err != nil {
+		t.Fatalf("failed to insert inside transaction: %v", err)
+	}
+	if err := tx.Rollback(); err != nil {
+		t.Fatalf("failed to roll back transaction: %v", err)
+	}
+
+	var count int
+	if err := db.QueryRow(countByID, 2).Scan(&count); err != nil {
+		t.Fatalf("failed to count rows after rollback: %v", err)
+	}
+	if count != 0 {
+		t.Fatalf("rolled-back write is visible: want 0 rows, got %d", count)
+	}
+
+	tx, err = db.Begin()
+	if err != nil {
+		t.Fatalf("failed to begin transaction: %v", err)
+	}
+	if _, err := tx.Exec(insert, 2, 1); err != nil {
+		t.Fatalf("failed to insert inside transaction: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("failed to commit transaction: %v", err)
+	}
+
+	if err := db.QueryRow(countByID, 2).Scan(&count); err != nil {
+		t.Fatalf("failed to count rows after commit: %v", err)
+	}
+	if count != 1 {
+		t.Fatalf("committed write is not visible: want 1 row, got %d", count)
+	}
+}
+
+// testConcurrentWrites has several goroutines race to increment the same
+// row through a SELECT ... FOR UPDATE/UPDATE pair and checks that every
+// increment survives, i.e. that the backend's locking actually serializes
+// the read-modify-write instead of losing updates.
+func testConcurrentWrites(t *testing.T, dbType string, factory func() store.Store) {
+	s := factory()
+	defer shutdown(t, s)
+	db := sqlHandleOf(t, s)
+
+	if err := createCheckTable(db); err != nil {
+		t.Fatalf("failed to create check table: %v", err)
+	}
+	insert := fmt.Sprintf("INSERT INTO %s (id, value) VALUES (%s, %s)",
+		migrationCheckTable, placeholder(dbType, 1), placeholder(dbType, 2))
+	if _, err := db.Exec(insert, 3, 0); err != nil {
+		t.Fatalf("failed to seed counter row: %v", err)
+	}
+
+	const writers = 10
+	var wg sync.WaitGroup
+	errs := make(chan error, writers)
+	wg.Add(writers)
+	for i := 0; i < writers; i++ {
+		go func() {
+			defer wg.Done()
+			errs <- incrementRow(db, dbType, 3)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("concurrent increment failed: %v", err)
+		}
+	}
+
+	var value int
+	query := fmt.Sprintf("SELECT value FROM %s WHERE id = %s", migrationCheckTable, placeholder(dbType, 1))
+	if err := db.QueryRow(query, 3).Scan(&value); err != nil {
+		t.Fatalf("failed to read back counter row: %v", err)
+	}
+	if value != writers {
+		t.Fatalf("lost update: %d concurrent increments produced a value of %d", writers, value)
+	}
+}
+
+// incrementRow reads id's value with a row lock, then writes value+1 back
+// inside the same transaction, so concurrent callers serialize on the lock
+// instead of racing.
+func incrementRow(db *sql.DB, dbType string, id int) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	selectForUpdate := fmt.Sprintf("SELECT value FROM %s WHERE id = %s FOR UPDATE",
+		migrationCheckTable, placeholder(dbType, 1))
+	var value int
+	if err := tx.QueryRow(selectForUpdate, id).Scan(&value); err != nil {
+		return err
+	}
+
+	update := fmt.Sprintf("UPDATE %s SET value = %s WHERE id = %s",
+		migrationCheckTable, placeholder(dbType, 1), placeholder(dbType, 2))
+	if _, err := tx.Exec(update, value+1, id); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func shutdown(t *testing.T, s store.Store) {
+	t.Helper()
+	if sd, ok := s.(shutdowner); ok {
+		if err := sd.Shutdown(); err != nil {
+			t.Errorf("failed to shut down store: %v", err)
+		}
+	}
+}